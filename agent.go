@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"chariot/pkg/provider"
+)
+
+func NewAgent(client provider.Provider, getUserMessage func() (string, bool), tools *ToolRegistry) *Agent {
+	return &Agent{
+		client:         client,
+		getUserMessage: getUserMessage,
+		tools:          tools,
+	}
+}
+
+type Agent struct {
+	client         provider.Provider
+	getUserMessage func() (string, bool)
+	tools          *ToolRegistry
+	// JSONMode makes the agent fall back to prompt-based tool calling:
+	// tool schemas go into a system prompt instead of the provider's
+	// native tool list, and the model's JSON reply is parsed back into a
+	// tool call or a plain response. Set this for models with no native
+	// tool-calling support.
+	JSONMode bool
+}
+
+func (a *Agent) Run(ctx context.Context) error {
+	conversation := []provider.Message{}
+
+	fmt.Println("Chat with Ollama (use 'ctrl-c' to quit)")
+
+	for {
+		fmt.Print("\x1b[94mYou\x1b[0m: ")
+		userInput, ok := a.getUserMessage()
+		if !ok {
+			break
+		}
+
+		conversation = append(conversation, provider.Message{
+			Role:    provider.RoleUser,
+			Content: userInput,
+		})
+
+		updated, err := a.RunTurn(ctx, conversation, nil)
+		if err != nil {
+			return err
+		}
+		conversation = updated
+	}
+
+	return nil
+}
+
+// RunTurn runs inference against conversation, dispatching any tool calls
+// and feeding their results back, until the model responds without calling
+// a tool. It returns the full conversation with every message it appended.
+// record, if non-nil, is called with each appended message as it's
+// produced - session commands use this to persist a turn incrementally
+// instead of waiting for RunTurn to return.
+func (a *Agent) RunTurn(ctx context.Context, conversation []provider.Message, record func(provider.Message)) ([]provider.Message, error) {
+	for {
+		message, err := a.converse(ctx, conversation)
+		if err != nil {
+			return conversation, err
+		}
+
+		conversation = append(conversation, message)
+		if record != nil {
+			record(message)
+		}
+
+		if len(message.ToolCalls) == 0 {
+			return conversation, nil
+		}
+
+		for _, toolCall := range message.ToolCalls {
+			result := a.executeTool(toolCall.Name, toolCall.Arguments)
+			toolMessage := provider.Message{
+				Role:       provider.RoleTool,
+				Name:       toolCall.Name,
+				ToolCallID: toolCall.ID,
+				Content:    result,
+			}
+			conversation = append(conversation, toolMessage)
+			if record != nil {
+				record(toolMessage)
+			}
+		}
+	}
+}
+
+// converse runs one round of inference, streaming assistant tokens to the
+// terminal as they arrive when the Provider supports it, and falling back
+// to a single blocking call otherwise. Tool calls are never printed token
+// by token: they're buffered by the Provider and only surface once the
+// model is done, since partial arguments aren't valid JSON to act on.
+func (a *Agent) converse(ctx context.Context, conversation []provider.Message) (provider.Message, error) {
+	if a.JSONMode {
+		return a.converseJSON(ctx, conversation)
+	}
+
+	streamer, ok := a.client.(provider.StreamingProvider)
+	if !ok {
+		response, err := a.runInference(ctx, conversation)
+		if err != nil {
+			return provider.Message{}, err
+		}
+		if len(response.Message.ToolCalls) == 0 {
+			fmt.Printf("\x1b[93mOllama\x1b[0m: %s\n", response.Message.Content)
+		}
+		return response.Message, nil
+	}
+
+	events, err := streamer.ChatStream(ctx, conversation, a.providerTools())
+	if err != nil {
+		return provider.Message{}, err
+	}
+
+	printedPrefix := false
+	var message provider.Message
+	var streamErr error
+	for event := range events {
+		switch event.Type {
+		case provider.EventToken:
+			if !printedPrefix {
+				fmt.Print("\x1b[93mOllama\x1b[0m: ")
+				printedPrefix = true
+			}
+			fmt.Print(event.Token)
+		case provider.EventToolCall:
+			if printedPrefix {
+				fmt.Println()
+				printedPrefix = false
+			}
+			fmt.Printf("\x1b[92mtool call\x1b[0m: %s\n", event.ToolCall.Name)
+		case provider.EventDone:
+			message = event.Message
+			streamErr = event.Err
+		}
+	}
+	if printedPrefix {
+		fmt.Println()
+	}
+	if streamErr != nil {
+		return provider.Message{}, streamErr
+	}
+
+	return message, nil
+}
+
+// converseJSON is converse's fallback for models with no native tool-calling
+// support: it injects a system message describing the available tools and
+// the required reply shape, asks the Provider to constrain its output to
+// JSON if it can, and parses the result back into a tool call or a plain
+// response.
+func (a *Agent) converseJSON(ctx context.Context, conversation []provider.Message) (provider.Message, error) {
+	prompted := append([]provider.Message{{
+		Role:    provider.RoleSystem,
+		Content: provider.RenderToolsPrompt(a.providerTools()),
+	}}, conversation...)
+
+	var response *provider.Response
+	var err error
+	if jsonProvider, ok := a.client.(provider.JSONModeProvider); ok {
+		response, err = jsonProvider.ChatJSON(ctx, prompted)
+	} else {
+		response, err = a.client.Chat(ctx, prompted, nil)
+	}
+	if err != nil {
+		return provider.Message{}, err
+	}
+
+	message := parseJSONModeReply(response.Message.Content)
+	if len(message.ToolCalls) == 0 {
+		fmt.Printf("\x1b[93mOllama\x1b[0m: %s\n", message.Content)
+	}
+	return message, nil
+}
+
+// parseJSONModeReply parses a JSON-mode completion into a tool call or a
+// plain response. A reply that doesn't parse as one of the two expected
+// shapes is treated as plain text, since the model still said something
+// worth showing the user even if it ignored the requested format.
+func parseJSONModeReply(content string) provider.Message {
+	var call struct {
+		Tool      string          `json:"tool"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal([]byte(content), &call); err == nil && call.Tool != "" {
+		return provider.Message{
+			Role: provider.RoleAssistant,
+			ToolCalls: []provider.ToolCall{{
+				Name:      call.Tool,
+				Arguments: call.Arguments,
+			}},
+		}
+	}
+
+	var plain struct {
+		Response string `json:"response"`
+	}
+	if err := json.Unmarshal([]byte(content), &plain); err == nil && plain.Response != "" {
+		return provider.Message{Role: provider.RoleAssistant, Content: plain.Response}
+	}
+
+	return provider.Message{Role: provider.RoleAssistant, Content: content}
+}
+
+func (a *Agent) executeTool(name string, input json.RawMessage) string {
+	toolDef, found := a.tools.Lookup(name)
+	if !found {
+		return "tool not found"
+	}
+
+	if a.tools.RequiresConfirmation(toolDef) && !a.confirm(name, input) {
+		return "user declined to run this tool"
+	}
+
+	fmt.Printf("\x1b[92mtool\x1b[0m: %s(%s)\n", name, input)
+	response, err := toolDef.Function(input)
+	if err != nil {
+		return err.Error()
+	}
+	return response
+}
+
+// confirm asks the user to approve running a dangerous tool call, reusing
+// the same stdin reader the REPL loop uses for ordinary messages.
+func (a *Agent) confirm(name string, input json.RawMessage) bool {
+	fmt.Printf("\x1b[91mrun %s(%s)? [y/N]\x1b[0m ", name, input)
+	answer, ok := a.getUserMessage()
+	if !ok {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(answer), "y")
+}
+
+func (a *Agent) runInference(ctx context.Context, conversation []provider.Message) (*provider.Response, error) {
+	return a.client.Chat(ctx, conversation, a.providerTools())
+}
+
+func (a *Agent) providerTools() []provider.Tool {
+	tools := a.tools.List()
+	providerTools := make([]provider.Tool, len(tools))
+	for i, tool := range tools {
+		providerTools[i] = provider.Tool{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Schema:      tool.InputSchema.Schema,
+		}
+	}
+	return providerTools
+}
@@ -2,21 +2,56 @@ package main
 
 import (
 	"bufio"
-	"bytes"
 	"context"
-	"encoding/json"
+	"flag"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
-	"path/filepath"
 
-	"github.com/invopop/jsonschema"
-	orderedmap "github.com/wk8/go-ordered-map/v2"
+	"chariot/pkg/provider"
 )
 
+// sessionSubcommands are the chariot subcommands that operate on persisted
+// sessions instead of starting a plain, ephemeral REPL.
+var sessionSubcommands = map[string]bool{
+	"new": true, "reply": true, "view": true, "rm": true, "ls": true, "edit": true,
+}
+
 func main() {
-	client := NewOllamaClient("http://localhost:11434", "qwen3:8b")
+	fs := flag.NewFlagSet("chariot", flag.ExitOnError)
+	var (
+		providerName = fs.String("provider", envOr("CHARIOT_PROVIDER", "ollama"), "backend to use: ollama, openai, or anthropic")
+		baseURL      = fs.String("base-url", os.Getenv("BASE_URL"), "base URL of the provider's API (defaults to the provider's usual endpoint)")
+		apiKey       = fs.String("api-key", os.Getenv("API_KEY"), "API key for the provider, if it requires one")
+		model        = fs.String("model", envOr("MODEL", "qwen3:8b"), "model name to request")
+		workdir      = fs.String("workdir", "", "working directory for the bash tool (defaults to the current directory)")
+		yolo         = fs.Bool("yolo", false, "run dangerous tools (write_file, edit_file, bash, http_fetch) without asking for confirmation")
+		jsonMode     = fs.Bool("json-mode", envOr("CHARIOT_JSON_MODE", "") != "", "describe tools in a system prompt and parse JSON replies instead of using the provider's native tool-calling (for models without it)")
+	)
+
+	// Flags must come before the subcommand and its arguments (e.g.
+	// "chariot -provider anthropic reply abc123", not
+	// "chariot reply abc123 -provider anthropic"): flag.Parse stops
+	// consuming flags at the first non-flag argument, which would
+	// otherwise be the subcommand itself.
+	fs.Parse(os.Args[1:])
+	args := fs.Args()
+
+	subcommand := ""
+	if len(args) > 0 && sessionSubcommands[args[0]] {
+		subcommand = args[0]
+		args = args[1:]
+	}
+	positional := args
+
+	// explicitFlags tracks which flags the user actually passed on this
+	// invocation, as opposed to ones that fell back to their env var or
+	// default. Session commands need this to tell "use what's on disk"
+	// apart from "override it", since -provider/-model share the same
+	// flags whether or not a session is involved.
+	explicitFlags := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { explicitFlags[f.Name] = true })
+
+	BashWorkdir = *workdir
 
 	scanner := bufio.NewScanner(os.Stdin)
 	getUserMessage := func() (string, bool) {
@@ -26,330 +61,61 @@ func main() {
 		return scanner.Text(), true
 	}
 
-	tools := []ToolDefinition{ReadFileDefinition, ListFilesDefinition}
-
-	agent := NewAgent(client, getUserMessage, tools)
-	err := agent.Run(context.TODO())
-	if err != nil {
-		fmt.Printf("Error: %s\n", err.Error())
-	}
-}
-
-// OllamaClient wraps HTTP calls to Ollama
-type OllamaClient struct {
-	baseURL string
-	model   string
-	client  *http.Client
-}
-
-func NewOllamaClient(baseURL, model string) *OllamaClient {
-	return &OllamaClient{
-		baseURL: baseURL,
-		model:   model,
-		client:  &http.Client{},
-	}
-}
-
-// Ollama API structures
-type OllamaMessage struct {
-	Role      string     `json:"role"`
-	Content   string     `json:"content"`
-	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
-}
-
-type ToolCall struct {
-	Function struct {
-		Name      string          `json:"name"`
-		Arguments json.RawMessage `json:"arguments"`
-	} `json:"function"`
-}
-
-type OllamaTool struct {
-	Type     string `json:"type"`
-	Function struct {
-		Name        string                 `json:"name"`
-		Description string                 `json:"description"`
-		Parameters  map[string]interface{} `json:"parameters"`
-	} `json:"function"`
-}
-
-type OllamaRequest struct {
-	Model    string          `json:"model"`
-	Messages []OllamaMessage `json:"messages"`
-	Tools    []OllamaTool    `json:"tools,omitempty"`
-	Stream   bool            `json:"stream"`
-}
-
-type OllamaResponse struct {
-	Message OllamaMessage `json:"message"`
-	Done    bool          `json:"done"`
-}
-
-func (c *OllamaClient) Chat(ctx context.Context, messages []OllamaMessage, tools []OllamaTool) (*OllamaResponse, error) {
-	reqBody := OllamaRequest{
-		Model:    c.model,
-		Messages: messages,
-		Tools:    tools,
-		Stream:   false,
-	}
-
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/chat", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var ollamaResp OllamaResponse
-	err = json.Unmarshal(body, &ollamaResp)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w, body: %s", err, string(body))
-	}
-
-	return &ollamaResp, nil
-}
-
-func NewAgent(client *OllamaClient, getUserMessage func() (string, bool), tools []ToolDefinition) *Agent {
-	return &Agent{
-		client:         client,
-		getUserMessage: getUserMessage,
-		tools:          tools,
-	}
-}
-
-type Agent struct {
-	client         *OllamaClient
-	getUserMessage func() (string, bool)
-	tools          []ToolDefinition
-}
-
-func (a *Agent) Run(ctx context.Context) error {
-	conversation := []OllamaMessage{}
-
-	fmt.Println("Chat with Ollama (use 'ctrl-c' to quit)")
-
-	readUserInput := true
-	for {
-		if readUserInput {
-			fmt.Print("\u001b[94mYou\u001b[0m: ")
-			userInput, ok := a.getUserMessage()
-			if !ok {
-				break
-			}
-
-			conversation = append(conversation, OllamaMessage{
-				Role:    "user",
-				Content: userInput,
-			})
-		}
-
-		response, err := a.runInference(ctx, conversation)
-		if err != nil {
-			return err
-		}
-
-		conversation = append(conversation, response.Message)
-
-		// Check if there are tool calls
-		if len(response.Message.ToolCalls) > 0 {
-			readUserInput = false
-			toolResults := []string{}
-
-			for _, toolCall := range response.Message.ToolCalls {
-				result := a.executeTool(toolCall.Function.Name, toolCall.Function.Arguments)
-				toolResults = append(toolResults, fmt.Sprintf("Tool %s result: %s", toolCall.Function.Name, result))
-			}
-
-			// Add tool results as a user message
-			conversation = append(conversation, OllamaMessage{
-				Role:    "user",
-				Content: fmt.Sprintf("Tool results: %v", toolResults),
-			})
-		} else {
-			// No tool calls, just print the response
-			fmt.Printf("\u001b[93mOllama\u001b[0m: %s\n", response.Message.Content)
-			readUserInput = true
-		}
-	}
-
-	return nil
-}
-
-func (a *Agent) executeTool(name string, input json.RawMessage) string {
-	var toolDef ToolDefinition
-	var found bool
-	for _, tool := range a.tools {
-		if tool.Name == name {
-			toolDef = tool
-			found = true
-			break
-		}
-	}
-	if !found {
-		return "tool not found"
-	}
-
-	fmt.Printf("\u001b[92mtool\u001b[0m: %s(%s)\n", name, input)
-	response, err := toolDef.Function(input)
-	if err != nil {
-		return err.Error()
-	}
-	return response
-}
-
-func (a *Agent) runInference(ctx context.Context, conversation []OllamaMessage) (*OllamaResponse, error) {
-	ollamaTools := []OllamaTool{}
-
-	for _, tool := range a.tools {
-		ollamaTool := OllamaTool{
-			Type: "function",
-		}
-		ollamaTool.Function.Name = tool.Name
-		ollamaTool.Function.Description = tool.Description
-
-		// Convert OrderedMap to regular map
-		properties := make(map[string]interface{})
-		if tool.InputSchema.Properties != nil {
-			for pair := tool.InputSchema.Properties.Oldest(); pair != nil; pair = pair.Next() {
-				properties[pair.Key] = pair.Value
-			}
-		}
-
-		// Convert the schema to Ollama format
-		params := map[string]interface{}{
-			"type":       "object",
-			"properties": properties,
-			"required":   []string{}, // Add required fields if needed
-		}
-		ollamaTool.Function.Parameters = params
-
-		ollamaTools = append(ollamaTools, ollamaTool)
-	}
-
-	return a.client.Chat(ctx, conversation, ollamaTools)
-}
-
-type ToolDefinition struct {
-	Name        string               `json:"name"`
-	Description string               `json:"description"`
-	InputSchema ToolInputSchemaParam `json:"input_schema"`
-	Function    func(input json.RawMessage) (string, error)
-}
-
-type ToolInputSchemaParam struct {
-	Properties *orderedmap.OrderedMap[string, *jsonschema.Schema]
-}
-
-var ReadFileDefinition = ToolDefinition{
-	Name:        "read_file",
-	Description: "Read the contents of a given relative file path. Use this when you want to see what's inside a file. Do not use this with directory names.",
-	InputSchema: ReadFileInputSchema,
-	Function:    ReadFile,
-}
-
-type ReadFileInput struct {
-	Path string `json:"path" jsonschema_description:"The relative path of a file in the working directory."`
-}
-
-var ReadFileInputSchema = GenerateSchema[ReadFileInput]()
-
-func ReadFile(input json.RawMessage) (string, error) {
-	readFileInput := ReadFileInput{}
-	err := json.Unmarshal(input, &readFileInput)
-	if err != nil {
-		panic(err)
-	}
-
-	content, err := os.ReadFile(readFileInput.Path)
-	if err != nil {
-		return "", err
-	}
-	return string(content), nil
-}
-
-func GenerateSchema[T any]() ToolInputSchemaParam {
-	reflector := jsonschema.Reflector{
-		AllowAdditionalProperties: false,
-		DoNotReference:            true,
-	}
-	var v T
-
-	schema := reflector.Reflect(v)
-
-	return ToolInputSchemaParam{
-		Properties: schema.Properties,
-	}
-}
-
-var ListFilesDefinition = ToolDefinition{
-	Name:        "list_files",
-	Description: "List files and directories at a given path. If no path is provided, lists files in the current directory.",
-	InputSchema: ListFilesInputSchema,
-	Function:    ListFiles,
-}
-
-type ListFilesInput struct {
-	Path string `json:"path,omitempty" jsonschema_description:"Optional relative path to list files from. Defaults to current directory if not provided."`
-}
-
-var ListFilesInputSchema = GenerateSchema[ListFilesInput]()
-
-func ListFiles(input json.RawMessage) (string, error) {
-	listFilesInput := ListFilesInput{}
-	err := json.Unmarshal(input, &listFilesInput)
-	if err != nil {
-		panic(err)
-	}
-
-	dir := "."
-	if listFilesInput.Path != "" {
-		dir = listFilesInput.Path
-	}
-
-	var files []string
-	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+	tools := NewToolRegistry(*yolo)
+	tools.Register(ReadFileDefinition)
+	tools.Register(ListFilesDefinition)
+	tools.Register(WriteFileDefinition)
+	tools.Register(EditFileDefinition)
+	tools.Register(BashDefinition)
+	tools.Register(HTTPFetchDefinition)
+
+	// newAgent builds an Agent against the given provider/model, using the
+	// current -base-url/-api-key/-yolo/-json-mode flags. Session commands
+	// call this once they know which provider/model to use - the session's
+	// own, unless the user explicitly overrode them on the command line.
+	newAgent := func(providerName, model string) (*Agent, error) {
+		client, err := provider.New(provider.Config{
+			Name:    providerName,
+			BaseURL: *baseURL,
+			APIKey:  *apiKey,
+			Model:   model,
+		})
 		if err != nil {
-			return err
+			return nil, err
 		}
-
-		relPath, err := filepath.Rel(dir, path)
+		agent := NewAgent(client, getUserMessage, tools)
+		agent.JSONMode = *jsonMode
+		return agent, nil
+	}
+
+	switch subcommand {
+	case "new":
+		runNewSession(newAgent, *providerName, *model)
+	case "reply":
+		runReplySession(newAgent, *providerName, *model, explicitFlags, positional)
+	case "view":
+		runViewSession(positional)
+	case "rm":
+		runRmSession(positional)
+	case "ls":
+		runLsSession()
+	case "edit":
+		runEditSession(newAgent, *providerName, *model, explicitFlags, positional)
+	default:
+		agent, err := newAgent(*providerName, *model)
 		if err != nil {
-			return err
+			fmt.Printf("Error: %s\n", err.Error())
+			os.Exit(1)
 		}
-
-		if relPath != "." {
-			if info.IsDir() {
-				files = append(files, relPath+"/")
-			} else {
-				files = append(files, relPath)
-			}
+		if err := agent.Run(context.TODO()); err != nil {
+			fmt.Printf("Error: %s\n", err.Error())
 		}
-		return nil
-	})
-
-	if err != nil {
-		return "", err
 	}
+}
 
-	result, err := json.Marshal(files)
-	if err != nil {
-		return "", err
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
 	}
-
-	return string(result), nil
+	return fallback
 }
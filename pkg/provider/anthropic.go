@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// AnthropicProvider talks to the Anthropic messages API.
+type AnthropicProvider struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+func NewAnthropicProvider(baseURL, apiKey, model string) *AnthropicProvider {
+	return &AnthropicProvider{
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{},
+	}
+}
+
+// anthropicBlock is a single entry of an Anthropic message's Content array.
+// Anthropic multiplexes text, tool_use, and tool_result onto one array
+// instead of giving each its own message role.
+type anthropicBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string           `json:"role"`
+	Content []anthropicBlock `json:"content"`
+}
+
+type anthropicTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"input_schema"`
+}
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	MaxTokens int                `json:"max_tokens"`
+	System    string             `json:"system,omitempty"`
+	Messages  []anthropicMessage `json:"messages"`
+	Tools     []anthropicTool    `json:"tools,omitempty"`
+}
+
+type anthropicResponse struct {
+	Content []anthropicBlock `json:"content"`
+}
+
+const anthropicMaxTokens = 4096
+
+func (p *AnthropicProvider) Chat(ctx context.Context, messages []Message, tools []Tool) (*Response, error) {
+	system, converted := toAnthropicMessages(messages)
+
+	reqBody := anthropicRequest{
+		Model:     p.model,
+		MaxTokens: anthropicMaxTokens,
+		System:    system,
+		Messages:  converted,
+		Tools:     toAnthropicTools(tools),
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/v1/messages", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var anthropicResp anthropicResponse
+	if err := json.Unmarshal(body, &anthropicResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w, body: %s", err, string(body))
+	}
+
+	return &Response{Message: fromAnthropicBlocks(anthropicResp.Content)}, nil
+}
+
+// toAnthropicMessages pulls any leading system message out of messages,
+// since Anthropic takes it as a top-level request field rather than a
+// message with role "system".
+func toAnthropicMessages(messages []Message) (system string, out []anthropicMessage) {
+	for _, m := range messages {
+		switch m.Role {
+		case RoleSystem:
+			system = m.Content
+		case RoleTool:
+			out = append(out, anthropicMessage{
+				Role: "user",
+				Content: []anthropicBlock{{
+					Type:      "tool_result",
+					ToolUseID: m.ToolCallID,
+					Content:   m.Content,
+				}},
+			})
+		case RoleAssistant:
+			var blocks []anthropicBlock
+			if m.Content != "" {
+				blocks = append(blocks, anthropicBlock{Type: "text", Text: m.Content})
+			}
+			for _, tc := range m.ToolCalls {
+				blocks = append(blocks, anthropicBlock{
+					Type:  "tool_use",
+					ID:    tc.ID,
+					Name:  tc.Name,
+					Input: tc.Arguments,
+				})
+			}
+			out = append(out, anthropicMessage{Role: "assistant", Content: blocks})
+		default:
+			out = append(out, anthropicMessage{
+				Role:    "user",
+				Content: []anthropicBlock{{Type: "text", Text: m.Content}},
+			})
+		}
+	}
+	return system, out
+}
+
+func fromAnthropicBlocks(blocks []anthropicBlock) Message {
+	msg := Message{Role: RoleAssistant}
+	for _, b := range blocks {
+		switch b.Type {
+		case "text":
+			msg.Content += b.Text
+		case "tool_use":
+			msg.ToolCalls = append(msg.ToolCalls, ToolCall{
+				ID:        b.ID,
+				Name:      b.Name,
+				Arguments: b.Input,
+			})
+		}
+	}
+	return msg
+}
+
+func toAnthropicTools(tools []Tool) []anthropicTool {
+	out := make([]anthropicTool, len(tools))
+	for i, tool := range tools {
+		out[i].Name = tool.Name
+		out[i].Description = tool.Description
+		out[i].InputSchema = toolParameters(tool)
+	}
+	return out
+}
@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// OllamaProvider talks to a local (or remote) Ollama server's /api/chat
+// endpoint.
+type OllamaProvider struct {
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+func NewOllamaProvider(baseURL, model string) *OllamaProvider {
+	return &OllamaProvider{
+		baseURL: baseURL,
+		model:   model,
+		client:  &http.Client{},
+	}
+}
+
+// ollamaMessage has no tool-call-id field: Ollama's /api/chat wire format
+// doesn't assign tool calls an ID, so a "tool" result is matched back to
+// its call by name and position in the conversation instead, the same way
+// Ollama's own chat convention does.
+type ollamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+	Name      string           `json:"name,omitempty"` // tool name, set on role "tool" results
+}
+
+type ollamaToolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+type ollamaTool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string                 `json:"name"`
+		Description string                 `json:"description"`
+		Parameters  map[string]interface{} `json:"parameters"`
+	} `json:"function"`
+}
+
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Tools    []ollamaTool    `json:"tools,omitempty"`
+	Stream   bool            `json:"stream"`
+	Format   string          `json:"format,omitempty"`
+}
+
+type ollamaResponse struct {
+	Message ollamaMessage `json:"message"`
+	Done    bool          `json:"done"`
+}
+
+func (p *OllamaProvider) Chat(ctx context.Context, messages []Message, tools []Tool) (*Response, error) {
+	return p.chat(ctx, ollamaRequest{
+		Model:    p.model,
+		Messages: toOllamaMessages(messages),
+		Tools:    toOllamaTools(tools),
+		Stream:   false,
+	})
+}
+
+// ChatJSON asks Ollama to constrain its reply to a single JSON object
+// (format: "json") and sends no native tools - it's the fallback for
+// models without tool-calling support, where the caller has already
+// described the available tools in the conversation's system prompt and
+// will parse the returned JSON itself.
+func (p *OllamaProvider) ChatJSON(ctx context.Context, messages []Message) (*Response, error) {
+	return p.chat(ctx, ollamaRequest{
+		Model:    p.model,
+		Messages: toOllamaMessages(messages),
+		Stream:   false,
+		Format:   "json",
+	})
+}
+
+func (p *OllamaProvider) chat(ctx context.Context, reqBody ollamaRequest) (*Response, error) {
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/chat", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var ollamaResp ollamaResponse
+	if err := json.Unmarshal(body, &ollamaResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w, body: %s", err, string(body))
+	}
+
+	return &Response{Message: fromOllamaMessage(ollamaResp.Message)}, nil
+}
+
+// ChatStream is like Chat but requests Stream: true and emits the response
+// incrementally: a Token event per content delta, ToolCall events once the
+// final chunk reveals any tool calls, and a closing Done event carrying the
+// fully assembled Message. Ollama's streaming wire format is a sequence of
+// newline-delimited JSON objects, which json.Decoder happily reads one at a
+// time without any custom framing.
+func (p *OllamaProvider) ChatStream(ctx context.Context, messages []Message, tools []Tool) (<-chan Event, error) {
+	reqBody := ollamaRequest{
+		Model:    p.model,
+		Messages: toOllamaMessages(messages),
+		Tools:    toOllamaTools(tools),
+		Stream:   true,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/chat", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+
+		decoder := json.NewDecoder(resp.Body)
+		var content string
+		var toolCalls []ollamaToolCall
+
+		for {
+			var chunk ollamaResponse
+			if err := decoder.Decode(&chunk); err != nil {
+				if err != io.EOF {
+					events <- Event{Type: EventDone, Err: fmt.Errorf("failed to decode stream chunk: %w", err)}
+				}
+				return
+			}
+
+			if chunk.Message.Content != "" {
+				content += chunk.Message.Content
+				events <- Event{Type: EventToken, Token: chunk.Message.Content}
+			}
+			toolCalls = append(toolCalls, chunk.Message.ToolCalls...)
+
+			if chunk.Done {
+				message := fromOllamaMessage(ollamaMessage{
+					Role:      "assistant",
+					Content:   content,
+					ToolCalls: toolCalls,
+				})
+				for _, tc := range message.ToolCalls {
+					events <- Event{Type: EventToolCall, ToolCall: tc}
+				}
+				events <- Event{Type: EventDone, Message: message}
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func toOllamaMessages(messages []Message) []ollamaMessage {
+	out := make([]ollamaMessage, len(messages))
+	for i, m := range messages {
+		out[i] = ollamaMessage{
+			Role:    string(m.Role),
+			Content: m.Content,
+			Name:    m.Name,
+		}
+		for _, tc := range m.ToolCalls {
+			var call ollamaToolCall
+			call.Function.Name = tc.Name
+			call.Function.Arguments = tc.Arguments
+			out[i].ToolCalls = append(out[i].ToolCalls, call)
+		}
+	}
+	return out
+}
+
+func fromOllamaMessage(m ollamaMessage) Message {
+	msg := Message{
+		Role:    Role(m.Role),
+		Content: m.Content,
+	}
+	for _, tc := range m.ToolCalls {
+		msg.ToolCalls = append(msg.ToolCalls, ToolCall{
+			Name:      tc.Function.Name,
+			Arguments: tc.Function.Arguments,
+		})
+	}
+	return msg
+}
+
+func toOllamaTools(tools []Tool) []ollamaTool {
+	out := make([]ollamaTool, len(tools))
+	for i, tool := range tools {
+		out[i].Type = "function"
+		out[i].Function.Name = tool.Name
+		out[i].Function.Description = tool.Description
+		out[i].Function.Parameters = toolParameters(tool)
+	}
+	return out
+}
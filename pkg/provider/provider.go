@@ -0,0 +1,203 @@
+// Package provider abstracts the chat backend an Agent talks to, so the
+// same agent loop can run against Ollama, OpenAI, or Anthropic without
+// knowing about any one API's wire format.
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+)
+
+// Role identifies who authored a Message in a conversation.
+type Role string
+
+const (
+	RoleSystem    Role = "system"
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+	RoleTool      Role = "tool"
+)
+
+// ToolCall is a single invocation of a tool requested by the model.
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments json.RawMessage
+}
+
+// Message is one turn in a conversation, in provider-agnostic form.
+// ToolCallID and Name are only set on RoleTool messages, identifying which
+// call the result belongs to.
+type Message struct {
+	Role       Role
+	Content    string
+	ToolCalls  []ToolCall
+	ToolCallID string
+	Name       string
+}
+
+// Tool describes a function the model may call. Schema is whatever
+// jsonschema.Reflector produced for the tool's input struct, kept intact so
+// required fields, enums, descriptions, and nested object types all survive
+// translation into a provider's native tool-call format.
+type Tool struct {
+	Name        string
+	Description string
+	Schema      *jsonschema.Schema
+}
+
+// Response is a single completion from a Provider.
+type Response struct {
+	Message Message
+}
+
+// Provider is a chat backend that can run inference over a conversation,
+// optionally offering the model a set of tools to call.
+type Provider interface {
+	Chat(ctx context.Context, messages []Message, tools []Tool) (*Response, error)
+}
+
+// EventType identifies the kind of Event a streaming Provider emits.
+type EventType int
+
+const (
+	// EventToken carries a chunk of incremental assistant text.
+	EventToken EventType = iota
+	// EventToolCall carries one fully-formed tool call, once its
+	// arguments have finished streaming.
+	EventToolCall
+	// EventDone marks the end of the stream and carries the fully
+	// assembled Message (equivalent to what Chat would have returned), or
+	// Err if the stream ended because the wire format couldn't be read.
+	EventDone
+)
+
+// Event is one piece of a streamed response. Err is only ever set on an
+// EventDone that resulted from a read/decode failure partway through the
+// stream; Message is the zero value in that case.
+type Event struct {
+	Type     EventType
+	Token    string
+	ToolCall ToolCall
+	Message  Message
+	Err      error
+}
+
+// StreamingProvider is implemented by Providers that can stream partial
+// output instead of blocking until the full response is ready. Callers
+// type-assert a Provider to this interface to decide whether to stream.
+type StreamingProvider interface {
+	Provider
+	ChatStream(ctx context.Context, messages []Message, tools []Tool) (<-chan Event, error)
+}
+
+// JSONModeProvider is implemented by Providers that can constrain a
+// completion to a single JSON object (Ollama's "format": "json", for
+// example). Callers use this for models with no native tool-calling
+// support: the tools are described in a system prompt instead, and the
+// model's JSON reply is parsed back into a tool call or a plain response.
+type JSONModeProvider interface {
+	Provider
+	ChatJSON(ctx context.Context, messages []Message) (*Response, error)
+}
+
+// Config selects and configures a Provider at startup.
+type Config struct {
+	Name    string // "ollama", "openai", or "anthropic"
+	BaseURL string
+	APIKey  string
+	Model   string
+}
+
+// New constructs the Provider named by cfg.Name, applying backend-specific
+// defaults for BaseURL when one isn't supplied.
+func New(cfg Config) (Provider, error) {
+	switch cfg.Name {
+	case "", "ollama":
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = "http://localhost:11434"
+		}
+		return NewOllamaProvider(baseURL, cfg.Model), nil
+	case "openai":
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = "https://api.openai.com"
+		}
+		return NewOpenAIProvider(baseURL, cfg.APIKey, cfg.Model), nil
+	case "anthropic":
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = "https://api.anthropic.com"
+		}
+		return NewAnthropicProvider(baseURL, cfg.APIKey, cfg.Model), nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q (want ollama, openai, or anthropic)", cfg.Name)
+	}
+}
+
+// toolParameters renders a Tool's schema into the {"type", "properties",
+// "required"} shape every provider's wire format wants for a function's
+// parameters. Each property is itself a *jsonschema.Schema, so per-field
+// description, enum, and nested object structure all marshal through as-is.
+func toolParameters(tool Tool) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	if tool.Schema != nil {
+		if tool.Schema.Properties != nil {
+			for pair := tool.Schema.Properties.Oldest(); pair != nil; pair = pair.Next() {
+				properties[pair.Key] = pair.Value
+			}
+		}
+		required = tool.Schema.Required
+	}
+	if required == nil {
+		required = []string{}
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// RenderToolsPrompt renders tools into a compact schema block for models
+// with no native tool-calling support, walking each tool's
+// *jsonschema.Schema so that per-field descriptions, enums, and
+// required-ness survive the trip into the prompt the same way they would
+// into a native tool-call schema. It ends with instructions for the exact
+// JSON shape the model must reply with.
+func RenderToolsPrompt(tools []Tool) string {
+	var b strings.Builder
+	b.WriteString("You can call the following tools. To call one, reply with ONLY a JSON object of the form {\"tool\": \"<name>\", \"arguments\": {...}}. If you don't need a tool, reply with ONLY {\"response\": \"<your reply>\"}.\n\n")
+
+	for _, tool := range tools {
+		fmt.Fprintf(&b, "- %s: %s\n", tool.Name, tool.Description)
+		if tool.Schema == nil || tool.Schema.Properties == nil {
+			continue
+		}
+
+		required := make(map[string]bool, len(tool.Schema.Required))
+		for _, name := range tool.Schema.Required {
+			required[name] = true
+		}
+
+		for pair := tool.Schema.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			field := pair.Value
+			detail := field.Type
+			if required[pair.Key] {
+				detail += ", required"
+			}
+			if len(field.Enum) > 0 {
+				detail += fmt.Sprintf(", one of %v", field.Enum)
+			}
+			fmt.Fprintf(&b, "  - %s (%s): %s\n", pair.Key, detail, field.Description)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,261 @@
+// Package session persists conversations to disk and lets them branch:
+// editing an earlier message forks a new path through the conversation
+// instead of overwriting history.
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"chariot/pkg/provider"
+)
+
+// NodeID identifies one message within a Session's tree.
+type NodeID string
+
+// Node is a single message in the conversation tree. Nodes are never
+// mutated or removed once appended: editing a message creates a sibling
+// Node under the same parent rather than changing the original, which is
+// what makes old branches keep working after a fork.
+type Node struct {
+	ID       NodeID           `json:"id"`
+	ParentID NodeID           `json:"parent_id,omitempty"`
+	Message  provider.Message `json:"message"`
+}
+
+// Session is a persisted, branching conversation. Nodes form the
+// append-only log; Heads indexes every branch tip so View can render the
+// whole tree instead of just the most recent path. The last entry in Heads
+// is the active branch that Reply and Edit act on.
+type Session struct {
+	ID         string           `json:"id"`
+	Provider   string           `json:"provider"`
+	Model      string           `json:"model"`
+	Nodes      map[NodeID]*Node `json:"nodes"`
+	Heads      []NodeID         `json:"heads"`
+	nextNodeID int
+}
+
+// Dir is where session files live, ~/.chariot/sessions.
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".chariot", "sessions"), nil
+}
+
+func path(id string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, id+".json"), nil
+}
+
+// New creates a fresh, empty session for the given provider/model.
+func New(providerName, model string) (*Session, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	return &Session{
+		ID:       id,
+		Provider: providerName,
+		Model:    model,
+		Nodes:    make(map[NodeID]*Node),
+	}, nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Load reads a session by ID from disk.
+func Load(id string) (*Session, error) {
+	file, err := path(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse session %s: %w", id, err)
+	}
+	for _, node := range s.Nodes {
+		if n := nodeIDSuffix(node.ID); n >= s.nextNodeID {
+			s.nextNodeID = n + 1
+		}
+	}
+	return &s, nil
+}
+
+// Save writes the session to ~/.chariot/sessions/<id>.json, creating the
+// directory if needed.
+func (s *Session) Save() error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file, err := path(s.ID)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0o644)
+}
+
+// List returns the IDs of every session on disk, sorted.
+func List() ([]string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		ids = append(ids, entry.Name()[:len(entry.Name())-len(".json")])
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Remove deletes a session's file from disk.
+func Remove(id string) error {
+	file, err := path(id)
+	if err != nil {
+		return err
+	}
+	return os.Remove(file)
+}
+
+// ActiveHead is the branch tip that Reply and Edit operate on: the most
+// recently created or forked-to head.
+func (s *Session) ActiveHead() NodeID {
+	if len(s.Heads) == 0 {
+		return ""
+	}
+	return s.Heads[len(s.Heads)-1]
+}
+
+func (s *Session) newNode(parent NodeID, msg provider.Message) *Node {
+	node := &Node{
+		ID:       NodeID(fmt.Sprintf("n%d", s.nextNodeID)),
+		ParentID: parent,
+		Message:  msg,
+	}
+	s.nextNodeID++
+	s.Nodes[node.ID] = node
+	return node
+}
+
+// Continue appends msg to the active branch, moving its head forward.
+func (s *Session) Continue(msg provider.Message) *Node {
+	node := s.newNode(s.ActiveHead(), msg)
+	if len(s.Heads) == 0 {
+		s.Heads = append(s.Heads, node.ID)
+	} else {
+		s.Heads[len(s.Heads)-1] = node.ID
+	}
+	return node
+}
+
+// Fork appends msg as a new child of parent and adds it as a new branch
+// head, leaving every existing head (including parent's old branch, if
+// any) untouched.
+func (s *Session) Fork(parent NodeID, msg provider.Message) *Node {
+	node := s.newNode(parent, msg)
+	s.Heads = append(s.Heads, node.ID)
+	return node
+}
+
+// Path walks from the root of head's branch down to head, in conversation
+// order.
+func (s *Session) Path(head NodeID) []*Node {
+	var reversed []*Node
+	for id := head; id != ""; {
+		node, ok := s.Nodes[id]
+		if !ok {
+			break
+		}
+		reversed = append(reversed, node)
+		id = node.ParentID
+	}
+
+	path := make([]*Node, len(reversed))
+	for i, node := range reversed {
+		path[len(reversed)-1-i] = node
+	}
+	return path
+}
+
+// Messages returns the conversation along the active branch, ready to feed
+// straight to an Agent.
+func (s *Session) Messages() []provider.Message {
+	path := s.Path(s.ActiveHead())
+	messages := make([]provider.Message, len(path))
+	for i, node := range path {
+		messages[i] = node.Message
+	}
+	return messages
+}
+
+// EditAt rewrites the user message at index (position along the active
+// branch, 0-based) and forks the session there: the edited message becomes
+// a new sibling of the original under the same parent, and the new branch
+// head, while the original message and everything built on top of it
+// remain reachable as their own branch.
+func (s *Session) EditAt(index int, newContent string) (*Node, error) {
+	path := s.Path(s.ActiveHead())
+	if index < 0 || index >= len(path) {
+		return nil, fmt.Errorf("message index %d out of range (session has %d messages)", index, len(path))
+	}
+
+	target := path[index]
+	if target.Message.Role != provider.RoleUser {
+		return nil, fmt.Errorf("message %d is a %s message, not a user message", index, target.Message.Role)
+	}
+
+	return s.Fork(target.ParentID, provider.Message{
+		Role:    provider.RoleUser,
+		Content: newContent,
+	}), nil
+}
+
+func nodeIDSuffix(id NodeID) int {
+	var n int
+	if _, err := fmt.Sscanf(string(id), "n%d", &n); err != nil {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"chariot/pkg/provider"
+	"chariot/pkg/session"
+)
+
+// newAgentFunc builds an Agent for a given provider/model pair, deferring
+// to main's flag values for everything else (base URL, API key, yolo,
+// JSON mode).
+type newAgentFunc func(providerName, model string) (*Agent, error)
+
+func runNewSession(newAgent newAgentFunc, providerName, model string) {
+	sess, err := session.New(providerName, model)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err.Error())
+		return
+	}
+	agent, err := newAgent(providerName, model)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err.Error())
+		return
+	}
+	fmt.Printf("new session: %s\n", sess.ID)
+	sessionREPL(agent, sess)
+}
+
+func runReplySession(newAgent newAgentFunc, flagProvider, flagModel string, explicitFlags map[string]bool, args []string) {
+	if len(args) != 1 {
+		fmt.Println("usage: chariot [flags] reply <id>")
+		return
+	}
+	sess, err := session.Load(args[0])
+	if err != nil {
+		fmt.Printf("Error: %s\n", err.Error())
+		return
+	}
+	agent, err := newAgent(sessionProvider(sess, flagProvider, explicitFlags), sessionModel(sess, flagModel, explicitFlags))
+	if err != nil {
+		fmt.Printf("Error: %s\n", err.Error())
+		return
+	}
+	sessionREPL(agent, sess)
+}
+
+// sessionProvider resolves the provider to run a session command against:
+// the value the user explicitly passed with -provider, if any, otherwise
+// the one the session was created with. This is what lets "chariot reply
+// <id>" just work without re-specifying the backend.
+func sessionProvider(sess *session.Session, flagProvider string, explicitFlags map[string]bool) string {
+	if explicitFlags["provider"] {
+		return flagProvider
+	}
+	return sess.Provider
+}
+
+// sessionModel is sessionProvider's counterpart for -model.
+func sessionModel(sess *session.Session, flagModel string, explicitFlags map[string]bool) string {
+	if explicitFlags["model"] {
+		return flagModel
+	}
+	return sess.Model
+}
+
+// sessionREPL is Agent.Run's loop, but backed by a persisted Session: every
+// message either side produces is appended to the session's active branch
+// and saved to disk before the next prompt, so a reply session survives a
+// crash or a ctrl-c.
+func sessionREPL(agent *Agent, sess *session.Session) {
+	fmt.Println("Chat with Ollama (use 'ctrl-c' to quit)")
+	for {
+		fmt.Print("\x1b[94mYou\x1b[0m: ")
+		userInput, ok := agent.getUserMessage()
+		if !ok {
+			return
+		}
+
+		sess.Continue(provider.Message{Role: provider.RoleUser, Content: userInput})
+		if err := sess.Save(); err != nil {
+			fmt.Printf("Error: %s\n", err.Error())
+			return
+		}
+
+		if _, err := agent.RunTurn(context.TODO(), sess.Messages(), func(m provider.Message) {
+			sess.Continue(m)
+		}); err != nil {
+			fmt.Printf("Error: %s\n", err.Error())
+			return
+		}
+
+		if err := sess.Save(); err != nil {
+			fmt.Printf("Error: %s\n", err.Error())
+			return
+		}
+	}
+}
+
+func runViewSession(args []string) {
+	if len(args) != 1 {
+		fmt.Println("usage: chariot [flags] view <id>")
+		return
+	}
+	sess, err := session.Load(args[0])
+	if err != nil {
+		fmt.Printf("Error: %s\n", err.Error())
+		return
+	}
+
+	active := sess.ActiveHead()
+	for i, head := range sess.Heads {
+		marker := ""
+		if head == active {
+			marker = " (active)"
+		}
+		fmt.Printf("branch %d%s:\n", i, marker)
+		for j, node := range sess.Path(head) {
+			fmt.Printf("  [%d] %s: %s\n", j, node.Message.Role, summarize(node.Message.Content))
+		}
+	}
+}
+
+func summarize(content string) string {
+	const maxLen = 80
+	if len(content) > maxLen {
+		return content[:maxLen] + "..."
+	}
+	return content
+}
+
+func runRmSession(args []string) {
+	if len(args) != 1 {
+		fmt.Println("usage: chariot [flags] rm <id>")
+		return
+	}
+	if err := session.Remove(args[0]); err != nil {
+		fmt.Printf("Error: %s\n", err.Error())
+	}
+}
+
+func runLsSession() {
+	ids, err := session.List()
+	if err != nil {
+		fmt.Printf("Error: %s\n", err.Error())
+		return
+	}
+	for _, id := range ids {
+		fmt.Println(id)
+	}
+}
+
+func runEditSession(newAgent newAgentFunc, flagProvider, flagModel string, explicitFlags map[string]bool, args []string) {
+	if len(args) != 2 {
+		fmt.Println("usage: chariot [flags] edit <id> <msg-index>")
+		return
+	}
+
+	sess, err := session.Load(args[0])
+	if err != nil {
+		fmt.Printf("Error: %s\n", err.Error())
+		return
+	}
+	agent, err := newAgent(sessionProvider(sess, flagProvider, explicitFlags), sessionModel(sess, flagModel, explicitFlags))
+	if err != nil {
+		fmt.Printf("Error: %s\n", err.Error())
+		return
+	}
+
+	index, err := strconv.Atoi(args[1])
+	if err != nil {
+		fmt.Printf("Error: invalid message index %q\n", args[1])
+		return
+	}
+
+	fmt.Print("\x1b[94mnew message\x1b[0m: ")
+	newContent, ok := agent.getUserMessage()
+	if !ok {
+		return
+	}
+
+	if _, err := sess.EditAt(index, newContent); err != nil {
+		fmt.Printf("Error: %s\n", err.Error())
+		return
+	}
+	if err := sess.Save(); err != nil {
+		fmt.Printf("Error: %s\n", err.Error())
+		return
+	}
+
+	if _, err := agent.RunTurn(context.TODO(), sess.Messages(), func(m provider.Message) {
+		sess.Continue(m)
+	}); err != nil {
+		fmt.Printf("Error: %s\n", err.Error())
+		return
+	}
+
+	if err := sess.Save(); err != nil {
+		fmt.Printf("Error: %s\n", err.Error())
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+// ToolRegistry owns tool registration and lookup, and enforces the
+// confirmation policy for tools marked Dangerous. It replaces the
+// hard-coded []ToolDefinition slice main used to build by hand.
+type ToolRegistry struct {
+	tools map[string]ToolDefinition
+	order []string // registration order, so tool lists stay deterministic
+	yolo  bool     // skip confirmation for dangerous tools when true
+}
+
+// NewToolRegistry creates an empty registry. When yolo is true, dangerous
+// tools run without asking for confirmation.
+func NewToolRegistry(yolo bool) *ToolRegistry {
+	return &ToolRegistry{
+		tools: make(map[string]ToolDefinition),
+		yolo:  yolo,
+	}
+}
+
+// Register adds a tool, or replaces an existing one with the same name.
+func (r *ToolRegistry) Register(def ToolDefinition) {
+	if _, exists := r.tools[def.Name]; !exists {
+		r.order = append(r.order, def.Name)
+	}
+	r.tools[def.Name] = def
+}
+
+// Lookup finds a tool by name.
+func (r *ToolRegistry) Lookup(name string) (ToolDefinition, bool) {
+	def, ok := r.tools[name]
+	return def, ok
+}
+
+// List returns every registered tool, in registration order.
+func (r *ToolRegistry) List() []ToolDefinition {
+	defs := make([]ToolDefinition, len(r.order))
+	for i, name := range r.order {
+		defs[i] = r.tools[name]
+	}
+	return defs
+}
+
+// RequiresConfirmation reports whether running def should pause and ask the
+// user first.
+func (r *ToolRegistry) RequiresConfirmation(def ToolDefinition) bool {
+	return def.Dangerous && !r.yolo
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// BashWorkdir is the directory bash commands run in. Empty means the
+// agent's own working directory.
+var BashWorkdir string
+
+const defaultBashTimeoutSec = 30
+
+var BashDefinition = ToolDefinition{
+	Name:        "bash",
+	Description: "Run a shell command and capture its stdout and stderr. Use this for anything not covered by a dedicated tool.",
+	InputSchema: BashInputSchema,
+	Dangerous:   true,
+	Function:    Bash,
+}
+
+type BashInput struct {
+	Command    string `json:"command" jsonschema_description:"The shell command to run."`
+	TimeoutSec int    `json:"timeout_sec,omitempty" jsonschema_description:"Maximum seconds to let the command run before it's killed. Defaults to 30."`
+}
+
+var BashInputSchema = GenerateSchema[BashInput]()
+
+func Bash(input json.RawMessage) (string, error) {
+	bashInput := BashInput{}
+	if err := json.Unmarshal(input, &bashInput); err != nil {
+		return "", err
+	}
+
+	timeoutSec := bashInput.TimeoutSec
+	if timeoutSec <= 0 {
+		timeoutSec = defaultBashTimeoutSec
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeoutSec)*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", bashInput.Command)
+	cmd.Dir = BashWorkdir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+	result := fmt.Sprintf("stdout:\n%s\nstderr:\n%s", stdout.String(), stderr.String())
+	if runErr != nil {
+		return result, fmt.Errorf("command failed: %w", runErr)
+	}
+	return result, nil
+}
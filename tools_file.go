@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var WriteFileDefinition = ToolDefinition{
+	Name:        "write_file",
+	Description: "Write content to a file at the given relative path, creating it (and any missing parent directories) if it doesn't exist, or overwriting it if it does.",
+	InputSchema: WriteFileInputSchema,
+	Dangerous:   true,
+	Function:    WriteFile,
+}
+
+type WriteFileInput struct {
+	Path    string `json:"path" jsonschema_description:"The relative path of the file to write."`
+	Content string `json:"content" jsonschema_description:"The content to write to the file."`
+}
+
+var WriteFileInputSchema = GenerateSchema[WriteFileInput]()
+
+func WriteFile(input json.RawMessage) (string, error) {
+	writeFileInput := WriteFileInput{}
+	if err := json.Unmarshal(input, &writeFileInput); err != nil {
+		return "", err
+	}
+
+	if dir := filepath.Dir(writeFileInput.Path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return "", err
+		}
+	}
+
+	if err := os.WriteFile(writeFileInput.Path, []byte(writeFileInput.Content), 0o644); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("wrote %d bytes to %s", len(writeFileInput.Content), writeFileInput.Path), nil
+}
+
+var EditFileDefinition = ToolDefinition{
+	Name:        "edit_file",
+	Description: "Edit a file by replacing an exact, unique occurrence of old_str with new_str. Fails if old_str doesn't appear in the file, or appears more than once.",
+	InputSchema: EditFileInputSchema,
+	Dangerous:   true,
+	Function:    EditFile,
+}
+
+type EditFileInput struct {
+	Path   string `json:"path" jsonschema_description:"The relative path of the file to edit."`
+	OldStr string `json:"old_str" jsonschema_description:"Text to search for - must match exactly once in the file."`
+	NewStr string `json:"new_str" jsonschema_description:"Text to replace old_str with."`
+}
+
+var EditFileInputSchema = GenerateSchema[EditFileInput]()
+
+func EditFile(input json.RawMessage) (string, error) {
+	editFileInput := EditFileInput{}
+	if err := json.Unmarshal(input, &editFileInput); err != nil {
+		return "", err
+	}
+
+	if editFileInput.OldStr == editFileInput.NewStr {
+		return "", fmt.Errorf("old_str and new_str must differ")
+	}
+
+	content, err := os.ReadFile(editFileInput.Path)
+	if err != nil {
+		return "", err
+	}
+
+	switch count := strings.Count(string(content), editFileInput.OldStr); count {
+	case 0:
+		return "", fmt.Errorf("old_str not found in %s", editFileInput.Path)
+	case 1:
+		// exactly one match, proceed
+	default:
+		return "", fmt.Errorf("old_str is not unique in %s: found %d occurrences", editFileInput.Path, count)
+	}
+
+	updated := strings.Replace(string(content), editFileInput.OldStr, editFileInput.NewStr, 1)
+	if err := os.WriteFile(editFileInput.Path, []byte(updated), 0o644); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("edited %s", editFileInput.Path), nil
+}
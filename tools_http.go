@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var httpFetchClient = &http.Client{Timeout: 30 * time.Second}
+
+var HTTPFetchDefinition = ToolDefinition{
+	Name:        "http_fetch",
+	Description: "Make an HTTP request and return the response status and body. Method defaults to GET.",
+	InputSchema: HTTPFetchInputSchema,
+	Dangerous:   true,
+	Function:    HTTPFetch,
+}
+
+type HTTPFetchInput struct {
+	URL     string            `json:"url" jsonschema_description:"The URL to request."`
+	Method  string            `json:"method,omitempty" jsonschema_description:"HTTP method to use. Defaults to GET."`
+	Headers map[string]string `json:"headers,omitempty" jsonschema_description:"Optional request headers."`
+	Body    string            `json:"body,omitempty" jsonschema_description:"Optional request body."`
+}
+
+var HTTPFetchInputSchema = GenerateSchema[HTTPFetchInput]()
+
+func HTTPFetch(input json.RawMessage) (string, error) {
+	fetchInput := HTTPFetchInput{}
+	if err := json.Unmarshal(input, &fetchInput); err != nil {
+		return "", err
+	}
+
+	method := fetchInput.Method
+	if method == "" {
+		method = "GET"
+	}
+
+	var body io.Reader
+	if fetchInput.Body != "" {
+		body = strings.NewReader(fetchInput.Body)
+	}
+
+	req, err := http.NewRequest(method, fetchInput.URL, body)
+	if err != nil {
+		return "", err
+	}
+	for key, value := range fetchInput.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := httpFetchClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("status: %s\n%s", resp.Status, respBody), nil
+}